@@ -4,32 +4,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math"
 	"net/http"
 	"os"
 	"regexp"
-	"strconv"
-	"strings"
 	"time"
-	"unicode"
 
 	"github.com/google/uuid"
+
+	"github.com/donghaof/receipt-processor-challenge/pkg/idutil"
+	"github.com/donghaof/receipt-processor-challenge/scoring"
+	"github.com/donghaof/receipt-processor-challenge/store"
 )
 
 // ItemStruct is for the one item from the receipt
-type ItemStruct struct {
-	ShortDescription *string
-	Price            *string
-}
+type ItemStruct = store.Item
 
 // ReceiptStruct is for the receipt
-type ReceiptStruct struct {
-	Retailer     *string
-	PurchaseDate *string
-	PurchaseTime *string
-	Items        *[]ItemStruct
-	Total        *string
-}
+type ReceiptStruct = store.Receipt
 
 // ReceiptIDResponse is for the response with the receipt ID
 type ReceiptIDResponse struct {
@@ -41,63 +32,222 @@ type PointsResponse struct {
 	Points int64
 }
 
-// The array with the processed receipts
-var receipts = make(map[string]*ReceiptStruct)
+// PointsBreakdownResponse is for the response of the points breakdown route
+type PointsBreakdownResponse struct {
+	Points    int64
+	Breakdown []scoring.RuleResult
+}
+
+// ReceiptFilterCommand is for the filter sub-object of a paged receipt search
+type ReceiptFilterCommand struct {
+	Retailer         *string
+	PurchaseDateFrom *string
+	PurchaseDateTo   *string
+	PurchaseTimeFrom *string
+	PurchaseTimeTo   *string
+	MinTotal         *float64
+	MaxTotal         *float64
+	MinItems         *int
+	MaxItems         *int
+	MinPoints        *int64
+	MaxPoints        *int64
+	Status           *string
+}
 
-// Checks whether the provided receipt was processed
-func wasReceiptProcessed(id string) bool {
-	return receipts[id] != nil
+// ReceiptPagedRequestCommand is for the body of the paged receipt search route
+type ReceiptPagedRequestCommand struct {
+	Page          int
+	PageSize      int
+	OrderBy       string
+	SortDirection string
+	Filter        ReceiptFilterCommand
 }
 
-// The handler for the process receipt route
-func processReceipt(w http.ResponseWriter, r *http.Request) {
-	// Checking whether this is the POST method
-	if r.Method != http.MethodPost {
-		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
-		return
+// ReceiptSearchResult is for a single receipt entry in a paged search response
+type ReceiptSearchResult struct {
+	Id           string
+	Retailer     *string
+	PurchaseDate *string
+	PurchaseTime *string
+	Items        *[]ItemStruct
+	Total        *string
+	Points       int64
+}
+
+// ReceiptPagedResponse is for the response of the paged receipt search route
+type ReceiptPagedResponse struct {
+	TotalCount int
+	Results    []ReceiptSearchResult
+}
+
+// BulkReceiptsRequest is for the body of the bulk process route
+type BulkReceiptsRequest struct {
+	Receipts []ReceiptStruct
+}
+
+// BulkReceiptResult is for a single receipt's outcome in a bulk process response
+type BulkReceiptResult struct {
+	Index int
+	Id    string `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+// BulkReceiptsResponse is for the response of the bulk process route
+type BulkReceiptsResponse struct {
+	Results []BulkReceiptResult
+}
+
+// ReceiptDetailResponse is for the response of the get receipt route, with the receipt's current
+// status and full status history alongside its fields and points
+type ReceiptDetailResponse struct {
+	Id            string
+	Retailer      *string
+	PurchaseDate  *string
+	PurchaseTime  *string
+	Items         *[]ItemStruct
+	Total         *string
+	Points        int64
+	Status        string
+	StatusHistory []store.StatusEntry
+}
+
+// The receipt status values accepted by the status workflow
+const (
+	StatusOpen           = "OPEN"
+	StatusNeedsAttention = "NEEDS_ATTENTION"
+	StatusResolved       = "RESOLVED"
+)
+
+// Reports whether status is one of the known receipt statuses
+func validStatus(status string) bool {
+	switch status {
+	case StatusOpen, StatusNeedsAttention, StatusResolved:
+		return true
+	default:
+		return false
 	}
+}
 
-	// Decoding the JSON into the struct
+// StatusUpdateCommand is for the body of the single receipt status update route
+type StatusUpdateCommand struct {
+	Status  string
+	Comment string
+}
+
+// LoadDataFromRequest decodes and validates the command from the request body, writing an error
+// response and returning a non-nil error if it is invalid
+func (c *StatusUpdateCommand) LoadDataFromRequest(w http.ResponseWriter, r *http.Request) error {
 	decoder := json.NewDecoder(r.Body)
-	rs := &ReceiptStruct{}
-	err := decoder.Decode(rs)
+	if err := decoder.Decode(c); err != nil {
+		http.Error(w, "The status update is invalid.", http.StatusBadRequest)
+		return err
+	}
+	if !validStatus(c.Status) {
+		http.Error(w, "The status update is invalid.", http.StatusBadRequest)
+		return fmt.Errorf("invalid status %q", c.Status)
+	}
+	return nil
+}
+
+// BulkStatusUpdateCommand is for the body of the bulk receipt status update route
+type BulkStatusUpdateCommand struct {
+	Comment    string
+	Status     string
+	ReceiptIds []string
+}
+
+// LoadDataFromRequest decodes and validates the command from the request body, writing an error
+// response and returning a non-nil error if it is invalid
+func (c *BulkStatusUpdateCommand) LoadDataFromRequest(w http.ResponseWriter, r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(c); err != nil {
+		http.Error(w, "The bulk status update is invalid.", http.StatusBadRequest)
+		return err
+	}
+	if !validStatus(c.Status) {
+		http.Error(w, "The bulk status update is invalid.", http.StatusBadRequest)
+		return fmt.Errorf("invalid status %q", c.Status)
+	}
+	if len(c.ReceiptIds) < 1 {
+		http.Error(w, "The bulk status update is invalid.", http.StatusBadRequest)
+		return errors.New("no receipt ids")
+	}
+	return nil
+}
+
+// BulkStatusResult is for a single receipt's outcome in a bulk status update response
+type BulkStatusResult struct {
+	Id    string
+	Error string `json:",omitempty"`
+}
+
+// BulkStatusResponse is for the response of the bulk receipt status update route
+type BulkStatusResponse struct {
+	Results []BulkStatusResult
+}
+
+// The backing store for processed receipts, selected in main by the STORE env var
+var receiptStore store.ReceiptStore
+
+// Opens the ReceiptStore selected by the STORE and STORE_DSN env vars, defaulting to an
+// in-memory store
+func openReceiptStore() (store.ReceiptStore, error) {
+	switch backend := os.Getenv("STORE"); backend {
+	case "", "memory":
+		return store.NewMemoryStore(), nil
+	case "sqlite":
+		dsn := os.Getenv("STORE_DSN")
+		if dsn == "" {
+			dsn = "receipts.db"
+		}
+		return store.NewSQLiteStore(dsn)
+	default:
+		return nil, fmt.Errorf("unknown STORE backend %q", backend)
+	}
+}
+
+// Generates a receipt id that is not already in use
+func generateReceiptID() (string, error) {
+	for {
+		id := uuid.New().String()
+		_, found, err := receiptStore.Get(id)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return id, nil
+		}
+	}
+}
+
+// Validates a receipt against the rules of the receipt processor, returning a descriptive error for the first rule violated
+func validateReceipt(rs *ReceiptStruct) error {
 	// Checking for the missing fields
-	if err != nil ||
-		rs.Retailer == nil ||
+	if rs.Retailer == nil ||
 		rs.PurchaseDate == nil ||
 		rs.PurchaseTime == nil ||
 		rs.Items == nil ||
 		rs.Total == nil {
-		http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-		fmt.Printf("Invalid receipt: missing fields\n")
-		return
+		return errors.New("missing fields")
 	}
 	// Checking whether the retailer matches the pattern
 	matchRetailer, _ := regexp.MatchString("^[\\w\\s\\-&]+$", *rs.Retailer)
 	if !matchRetailer {
-		http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-		fmt.Printf("Invalid receipt: invalid retailer\n")
-		return
+		return errors.New("invalid retailer")
 	}
 	// Checking whether the purchase date is in the correct format
 	_, errPurchaseDate := time.Parse("2006-01-02", *rs.PurchaseDate)
 	if errPurchaseDate != nil {
-		http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-		fmt.Printf("Invalid receipt: invalid purchase date\n")
-		return
+		return errors.New("invalid purchase date")
 	}
 	// Checking whether the purchase time is in the correct format
 	_, errPurchaseTime := time.Parse("15:04", *rs.PurchaseTime)
 	if errPurchaseTime != nil {
-		http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-		fmt.Printf("Invalid receipt: invalid purchase time\n")
-		return
+		return errors.New("invalid purchase time")
 	}
 	// Checking whether there are some items
 	if len(*rs.Items) < 1 {
-		http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-		fmt.Printf("Invalid receipt: no items\n")
-		return
+		return errors.New("no items")
 	}
 	// Checking the items
 	shortDescriptionRegExp, _ := regexp.Compile(`^[\w\s\-]+$`)
@@ -106,34 +256,67 @@ func processReceipt(w http.ResponseWriter, r *http.Request) {
 		// Checking whether the all fields are filled
 		if item.ShortDescription == nil ||
 			item.Price == nil {
-			http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-			fmt.Printf("Invalid receipt: item with missing fields\n")
-			return
+			return errors.New("item with missing fields")
 		}
 		// Checking the short description
 		if !shortDescriptionRegExp.MatchString(*item.ShortDescription) {
-			http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-			fmt.Printf("Invalid receipt: invalid short description in the item\n")
-			return
+			return errors.New("invalid short description in the item")
 		}
 		// Checking the price
 		if !priceRegExp.MatchString(*item.Price) {
-			http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
-			fmt.Printf("Invalid receipt: invalid price in the item\n")
-			return
+			return errors.New("invalid price in the item")
 		}
 	}
+	return nil
+}
 
-	// Generating the new unique id
-	var id uuid.UUID
-	for id = uuid.New(); wasReceiptProcessed(id.String()); id = uuid.New() {
+// Stores a validated receipt under a newly generated id and returns that id
+func storeReceipt(rs *ReceiptStruct) (string, error) {
+	id, err := generateReceiptID()
+	if err != nil {
+		return "", err
 	}
+	if err := receiptStore.Save(id, rs, calculateReceiptPoints(rs)); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// The handler for the process receipt route
+func processReceipt(w http.ResponseWriter, r *http.Request) {
+	// Checking whether this is the POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Decoding the JSON into the struct
+	decoder := json.NewDecoder(r.Body)
+	rs := &ReceiptStruct{}
+	err := decoder.Decode(rs)
+	if err != nil {
+		http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
+		fmt.Printf("Invalid receipt: %s\n", err)
+		return
+	}
+	// Validating the receipt
+	if err = validateReceipt(rs); err != nil {
+		http.Error(w, "The receipt is invalid.", http.StatusBadRequest)
+		fmt.Printf("Invalid receipt: %s\n", err)
+		return
+	}
+
 	// Saving the receipt
-	receipts[id.String()] = rs
+	id, err := storeReceipt(rs)
+	if err != nil {
+		http.Error(w, "Error storing the receipt.", http.StatusInternalServerError)
+		fmt.Printf("Error storing receipt: %s\n", err)
+		return
+	}
 
 	// Preparing the response
 	rir := ReceiptIDResponse{
-		Id: id.String(),
+		Id: id,
 	}
 	// Writing the response
 	w.Header().Set("Content-Type", "application/json")
@@ -144,6 +327,90 @@ func processReceipt(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// The handler for the bulk process receipts route. The `mode` query parameter selects between
+// "all-or-nothing" (the default), where any invalid receipt aborts the whole batch with no
+// insertions, and "best-effort", where valid receipts are stored and invalid ones are reported
+// alongside them.
+func processReceiptsBulk(w http.ResponseWriter, r *http.Request) {
+	// Checking whether this is the POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Decoding the JSON into the struct
+	decoder := json.NewDecoder(r.Body)
+	br := &BulkReceiptsRequest{}
+	err := decoder.Decode(br)
+	if err != nil || len(br.Receipts) < 1 {
+		http.Error(w, "The bulk request is invalid.", http.StatusBadRequest)
+		fmt.Printf("Invalid bulk request: %v\n", err)
+		return
+	}
+
+	// Resolving the mode
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = "all-or-nothing"
+	}
+	if mode != "all-or-nothing" && mode != "best-effort" {
+		http.Error(w, "The mode is invalid.", http.StatusBadRequest)
+		fmt.Printf("Invalid bulk request: invalid mode %q\n", mode)
+		return
+	}
+
+	// Validating every receipt up front
+	results := make([]BulkReceiptResult, len(br.Receipts))
+	anyInvalid := false
+	for i := range br.Receipts {
+		if err := validateReceipt(&br.Receipts[i]); err != nil {
+			results[i] = BulkReceiptResult{Index: i, Error: err.Error()}
+			anyInvalid = true
+		} else {
+			results[i] = BulkReceiptResult{Index: i}
+		}
+	}
+
+	// In all-or-nothing mode, a single invalid receipt rejects the whole batch with no insertions
+	if mode == "all-or-nothing" && anyInvalid {
+		fmt.Printf("Invalid bulk request: one or more receipts failed validation\n")
+		brr := BulkReceiptsResponse{Results: results}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(brr); err != nil {
+			fmt.Printf("Error encoding response: %v\n", err)
+		}
+		return
+	}
+
+	// Storing the valid receipts
+	for i := range br.Receipts {
+		if results[i].Error != "" {
+			continue
+		}
+		id, err := storeReceipt(&br.Receipts[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Id = id
+	}
+
+	// Preparing the response
+	brr := BulkReceiptsResponse{Results: results}
+	// Writing the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(brr); err != nil {
+		fmt.Printf("Error encoding response: %v\n", err)
+	}
+}
+
+// Computes the points awarded for a receipt
+func calculateReceiptPoints(receipt *ReceiptStruct) int64 {
+	return scoring.ScoreReceipt(receipt).Points
+}
+
 // The handler for the points awarded for the receipt route
 func getReceiptPoints(w http.ResponseWriter, r *http.Request) {
 	// Checking whether this is the GET method
@@ -154,67 +421,280 @@ func getReceiptPoints(w http.ResponseWriter, r *http.Request) {
 
 	id := r.PathValue("id")
 	// Checking whether the id matches the pattern
-	matchId, _ := regexp.MatchString("^\\S+$", id)
-	if !matchId {
+	if !idutil.ValidUUID(id) {
 		http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
 		fmt.Printf("Invalid receipt id\n")
 		return
 	}
 	// Checking whether the such receipts exists
-	if !wasReceiptProcessed(id) {
+	sr, found, err := receiptStore.Get(id)
+	if err != nil {
+		http.Error(w, "Error looking up the receipt.", http.StatusInternalServerError)
+		fmt.Printf("Error looking up receipt: %s\n", err)
+		return
+	}
+	if !found {
 		http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
 		fmt.Printf("No receipt with the such id\n")
 		return
 	}
 
-	// Counting the points
-	var points int64 = 0
-	receipt := receipts[id]
-	// One point for every alphanumeric character in the retailer name
-	for _, char := range *receipt.Retailer {
-		if unicode.IsLetter(char) || unicode.IsDigit(char) {
-			points++
+	// Preparing the response
+	pr := PointsResponse{
+		Points: sr.Points,
+	}
+	// Writing the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(pr)
+	if err != nil {
+		fmt.Printf("Error encoding response: %v\n", err)
+	}
+}
+
+// The handler for the itemized points breakdown route
+func getReceiptPointsBreakdown(w http.ResponseWriter, r *http.Request) {
+	// Checking whether this is the GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	// Checking whether the id matches the pattern
+	if !idutil.ValidUUID(id) {
+		http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
+		fmt.Printf("Invalid receipt id\n")
+		return
+	}
+	// Checking whether the such receipts exists
+	sr, found, err := receiptStore.Get(id)
+	if err != nil {
+		http.Error(w, "Error looking up the receipt.", http.StatusInternalServerError)
+		fmt.Printf("Error looking up receipt: %s\n", err)
+		return
+	}
+	if !found {
+		http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
+		fmt.Printf("No receipt with the such id\n")
+		return
+	}
+
+	// Preparing the response
+	score := scoring.ScoreReceipt(&sr.Receipt)
+	pbr := PointsBreakdownResponse{
+		Points:    score.Points,
+		Breakdown: score.Breakdown,
+	}
+	// Writing the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(pbr)
+	if err != nil {
+		fmt.Printf("Error encoding response: %v\n", err)
+	}
+}
+
+// The handler for the get receipt route, returning the receipt's fields, points, current status
+// and full status history
+func getReceipt(w http.ResponseWriter, r *http.Request) {
+	// Checking whether this is the GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	// Checking whether the id matches the pattern
+	if !idutil.ValidUUID(id) {
+		http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
+		fmt.Printf("Invalid receipt id\n")
+		return
+	}
+	// Checking whether the such receipts exists
+	sr, found, err := receiptStore.Get(id)
+	if err != nil {
+		http.Error(w, "Error looking up the receipt.", http.StatusInternalServerError)
+		fmt.Printf("Error looking up receipt: %s\n", err)
+		return
+	}
+	if !found {
+		http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
+		fmt.Printf("No receipt with the such id\n")
+		return
+	}
+
+	// Preparing the response
+	rdr := ReceiptDetailResponse{
+		Id:            sr.Id,
+		Retailer:      sr.Retailer,
+		PurchaseDate:  sr.PurchaseDate,
+		PurchaseTime:  sr.PurchaseTime,
+		Items:         sr.Items,
+		Total:         sr.Total,
+		Points:        sr.Points,
+		Status:        sr.Status,
+		StatusHistory: sr.StatusHistory,
+	}
+	// Writing the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(rdr)
+	if err != nil {
+		fmt.Printf("Error encoding response: %v\n", err)
+	}
+}
+
+// The handler for the single receipt status update route
+func updateReceiptStatus(w http.ResponseWriter, r *http.Request) {
+	// Checking whether this is the PATCH method
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	if !idutil.ValidUUID(id) {
+		http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
+		fmt.Printf("Invalid receipt id\n")
+		return
+	}
+	cmd := &StatusUpdateCommand{}
+	if err := cmd.LoadDataFromRequest(w, r); err != nil {
+		fmt.Printf("Invalid status update: %s\n", err)
+		return
+	}
+
+	if err := receiptStore.UpdateStatus(id, cmd.Status, cmd.Comment); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, "No receipt found for that ID.", http.StatusNotFound)
+			return
 		}
+		http.Error(w, "Error updating the receipt status.", http.StatusInternalServerError)
+		fmt.Printf("Error updating receipt status: %s\n", err)
+		return
 	}
-	// 50 points if the total is a round dollar amount with no cents
-	total, _ := strconv.ParseFloat(*receipt.Total, 64)
-	if math.Mod(total, 1) == 0 {
-		points += 50
-	}
-	// 25 points if the total is a multiple of `0.25`.
-	if math.Mod(total, 0.25) == 0 {
-		points += 25
-	}
-	// 5 points for every two items on the receipt
-	points += int64((len(*receipt.Items) / 2) * 5)
-	// If the trimmed length of the item description is a multiple of 3, multiply the price by `0.2` and round up to the nearest integer. The result is the number of points earned
-	for _, item := range *receipt.Items {
-		// The trimmed length of the item description
-		trimmedLength := len(strings.TrimSpace(*item.ShortDescription))
-		if trimmedLength%3 == 0 {
-			price, _ := strconv.ParseFloat(*item.Price, 64)
-			points += int64(math.Ceil(price * 0.2))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// The handler for the bulk receipt status update route
+func updateReceiptStatusBulk(w http.ResponseWriter, r *http.Request) {
+	// Checking whether this is the POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cmd := &BulkStatusUpdateCommand{}
+	if err := cmd.LoadDataFromRequest(w, r); err != nil {
+		fmt.Printf("Invalid bulk status update: %s\n", err)
+		return
+	}
+
+	// Updating the status of every receipt, collecting per-id errors
+	results := make([]BulkStatusResult, len(cmd.ReceiptIds))
+	for i, id := range cmd.ReceiptIds {
+		if err := receiptStore.UpdateStatus(id, cmd.Status, cmd.Comment); err != nil {
+			results[i] = BulkStatusResult{Id: id, Error: err.Error()}
+			continue
 		}
+		results[i] = BulkStatusResult{Id: id}
 	}
-	// 6 points if the day in the purchase date is odd
-	parsedDate, _ := time.Parse("2006-01-02", *receipt.PurchaseDate)
-	if parsedDate.Day()%2 != 0 {
-		points += 6
+
+	// Preparing the response
+	bsr := BulkStatusResponse{Results: results}
+	// Writing the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(bsr); err != nil {
+		fmt.Printf("Error encoding response: %v\n", err)
+	}
+}
+
+// Converts the filter sub-object of a search command into a store.Filter
+func toStoreFilter(filter ReceiptFilterCommand) store.Filter {
+	return store.Filter{
+		Retailer:         filter.Retailer,
+		PurchaseDateFrom: filter.PurchaseDateFrom,
+		PurchaseDateTo:   filter.PurchaseDateTo,
+		PurchaseTimeFrom: filter.PurchaseTimeFrom,
+		PurchaseTimeTo:   filter.PurchaseTimeTo,
+		MinTotal:         filter.MinTotal,
+		MaxTotal:         filter.MaxTotal,
+		MinItems:         filter.MinItems,
+		MaxItems:         filter.MaxItems,
+		MinPoints:        filter.MinPoints,
+		MaxPoints:        filter.MaxPoints,
+		Status:           filter.Status,
 	}
-	// 10 points if the time of purchase is after 2:00pm and before 4:00pm
-	parsedTime, _ := time.Parse("15:04", *receipt.PurchaseTime)
-	if parsedTime.Hour() >= 14 && parsedTime.Hour() < 16 {
-		points += 10
+}
+
+// The handler for the paged/filterable receipt search route
+func searchReceipts(w http.ResponseWriter, r *http.Request) {
+	// Checking whether this is the POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Decoding the JSON into the command struct
+	decoder := json.NewDecoder(r.Body)
+	cmd := &ReceiptPagedRequestCommand{}
+	err := decoder.Decode(cmd)
+	if err != nil {
+		http.Error(w, "The search request is invalid.", http.StatusBadRequest)
+		fmt.Printf("Invalid search request: %s\n", err)
+		return
+	}
+	// Applying the defaults
+	if cmd.Page < 1 {
+		cmd.Page = 1
+	}
+	if cmd.PageSize < 1 {
+		cmd.PageSize = 10
+	}
+	if cmd.OrderBy == "" {
+		cmd.OrderBy = "retailer"
+	}
+	if cmd.SortDirection == "" {
+		cmd.SortDirection = "asc"
+	}
+
+	// Fetching the matching, sorted and paged receipts from the store
+	stored, totalCount, err := receiptStore.List(toStoreFilter(cmd.Filter), store.Page{
+		Number:        cmd.Page,
+		Size:          cmd.PageSize,
+		OrderBy:       cmd.OrderBy,
+		SortDirection: cmd.SortDirection,
+	})
+	if err != nil {
+		http.Error(w, "Error searching receipts.", http.StatusInternalServerError)
+		fmt.Printf("Error searching receipts: %s\n", err)
+		return
 	}
 
 	// Preparing the response
-	pr := PointsResponse{
-		Points: points,
+	results := make([]ReceiptSearchResult, len(stored))
+	for i, sr := range stored {
+		results[i] = ReceiptSearchResult{
+			Id:           sr.Id,
+			Retailer:     sr.Retailer,
+			PurchaseDate: sr.PurchaseDate,
+			PurchaseTime: sr.PurchaseTime,
+			Items:        sr.Items,
+			Total:        sr.Total,
+			Points:       sr.Points,
+		}
+	}
+	psr := ReceiptPagedResponse{
+		TotalCount: totalCount,
+		Results:    results,
 	}
 	// Writing the response
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	err := json.NewEncoder(w).Encode(pr)
+	err = json.NewEncoder(w).Encode(psr)
 	if err != nil {
 		fmt.Printf("Error encoding response: %v\n", err)
 	}
@@ -222,12 +702,26 @@ func getReceiptPoints(w http.ResponseWriter, r *http.Request) {
 
 // The entry point of the application
 func main() {
+	// Opening the receipt store
+	var err error
+	receiptStore, err = openReceiptStore()
+	if err != nil {
+		fmt.Printf("error opening receipt store: %s\n", err)
+		os.Exit(1)
+	}
+
 	// Setting the handlers for the routes
 	http.HandleFunc("/receipts/process", processReceipt)
+	http.HandleFunc("/receipts/process/bulk", processReceiptsBulk)
 	http.HandleFunc("/receipts/{id}/points", getReceiptPoints)
+	http.HandleFunc("/receipts/{id}/points/breakdown", getReceiptPointsBreakdown)
+	http.HandleFunc("/receipts/{id}/status", updateReceiptStatus)
+	http.HandleFunc("/receipts/{id}", getReceipt)
+	http.HandleFunc("/receipts/search", searchReceipts)
+	http.HandleFunc("/receipts/status/bulk", updateReceiptStatusBulk)
 
 	// Starting the server
-	err := http.ListenAndServe(":80", nil)
+	err = http.ListenAndServe(":80", nil)
 	if errors.Is(err, http.ErrServerClosed) {
 		fmt.Printf("server closed\n")
 	} else if err != nil {
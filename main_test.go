@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/donghaof/receipt-processor-challenge/store"
+)
+
+// resetReceiptStore points the global receiptStore at a fresh MemoryStore so handler tests don't
+// leak state into one another
+func resetReceiptStore() {
+	receiptStore = store.NewMemoryStore()
+}
+
+// newTestMux registers the same routes as main, so handler tests exercise routing (and therefore
+// r.PathValue) the same way the running server does
+func newTestMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/receipts/process", processReceipt)
+	mux.HandleFunc("/receipts/process/bulk", processReceiptsBulk)
+	mux.HandleFunc("/receipts/{id}/points", getReceiptPoints)
+	mux.HandleFunc("/receipts/{id}/points/breakdown", getReceiptPointsBreakdown)
+	mux.HandleFunc("/receipts/{id}/status", updateReceiptStatus)
+	mux.HandleFunc("/receipts/{id}", getReceipt)
+	mux.HandleFunc("/receipts/search", searchReceipts)
+	mux.HandleFunc("/receipts/status/bulk", updateReceiptStatusBulk)
+	return mux
+}
+
+func postJSON(t *testing.T, mux *http.ServeMux, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	buf, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshaling request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(buf))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func sampleReceiptStruct() ReceiptStruct {
+	retailer := "Target"
+	purchaseDate := "2022-01-01"
+	purchaseTime := "13:01"
+	total := "35.35"
+	items := []ItemStruct{
+		{ShortDescription: strPtr("Mountain Dew 12PK"), Price: strPtr("6.49")},
+		{ShortDescription: strPtr("Emils Cheese Pizza"), Price: strPtr("12.25")},
+	}
+	return ReceiptStruct{
+		Retailer:     &retailer,
+		PurchaseDate: &purchaseDate,
+		PurchaseTime: &purchaseTime,
+		Items:        &items,
+		Total:        &total,
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func int64Ptr(i int64) *int64 { return &i }
+
+func TestSearchReceiptsEmptyPage(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	rec := postJSON(t, mux, "/receipts/search", ReceiptPagedRequestCommand{})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp ReceiptPagedResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.TotalCount != 0 {
+		t.Errorf("TotalCount = %d, want 0", resp.TotalCount)
+	}
+	if len(resp.Results) != 0 {
+		t.Errorf("len(Results) = %d, want 0", len(resp.Results))
+	}
+}
+
+func TestSearchReceiptsPointsBoundaries(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	rs := sampleReceiptStruct()
+	if err := receiptStore.Save("id-low", &rs, 10); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := receiptStore.Save("id-high", &rs, 90); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	rec := postJSON(t, mux, "/receipts/search", ReceiptPagedRequestCommand{
+		Filter: ReceiptFilterCommand{MinPoints: int64Ptr(50), MaxPoints: int64Ptr(100)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp ReceiptPagedResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.TotalCount != 1 {
+		t.Fatalf("TotalCount = %d, want 1", resp.TotalCount)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Id != "id-high" {
+		t.Fatalf("Results = %+v, want only id-high", resp.Results)
+	}
+}
+
+func TestSearchReceiptsSortDirection(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	rs := sampleReceiptStruct()
+	if err := receiptStore.Save("id-low", &rs, 10); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := receiptStore.Save("id-high", &rs, 90); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	rec := postJSON(t, mux, "/receipts/search", ReceiptPagedRequestCommand{
+		OrderBy:       "points",
+		SortDirection: "desc",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp ReceiptPagedResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Id != "id-high" {
+		t.Fatalf("Results = %+v, want id-high first in desc order", resp.Results)
+	}
+}
+
+func TestProcessReceiptsBulkAllOrNothing(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	valid := sampleReceiptStruct()
+	invalid := sampleReceiptStruct()
+	invalid.Retailer = nil
+
+	rec := postJSON(t, mux, "/receipts/process/bulk", BulkReceiptsRequest{
+		Receipts: []ReceiptStruct{valid, invalid},
+	})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+
+	var resp BulkReceiptsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("len(Results) = %d, want 2", len(resp.Results))
+	}
+	if resp.Results[0].Id != "" {
+		t.Errorf("Results[0].Id = %q, want empty (all-or-nothing should not insert anything)", resp.Results[0].Id)
+	}
+	if resp.Results[1].Error == "" {
+		t.Errorf("Results[1].Error = %q, want a validation error", resp.Results[1].Error)
+	}
+
+	if _, total, err := receiptStore.List(store.Filter{}, store.Page{Number: 1, Size: 10, OrderBy: "retailer", SortDirection: "asc"}); err != nil {
+		t.Fatalf("List: %v", err)
+	} else if total != 0 {
+		t.Errorf("total stored = %d, want 0 (all-or-nothing should not insert anything)", total)
+	}
+}
+
+func TestProcessReceiptsBulkBestEffort(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	valid := sampleReceiptStruct()
+	invalid := sampleReceiptStruct()
+	invalid.Retailer = nil
+
+	buf, err := json.Marshal(BulkReceiptsRequest{Receipts: []ReceiptStruct{valid, invalid}})
+	if err != nil {
+		t.Fatalf("marshaling request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/receipts/process/bulk?mode=best-effort", bytes.NewReader(buf))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp BulkReceiptsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Results[0].Id == "" {
+		t.Errorf("Results[0].Id = %q, want the valid receipt to be stored", resp.Results[0].Id)
+	}
+	if resp.Results[1].Error == "" {
+		t.Errorf("Results[1].Error = %q, want a validation error for the invalid receipt", resp.Results[1].Error)
+	}
+
+	if _, total, err := receiptStore.List(store.Filter{}, store.Page{Number: 1, Size: 10, OrderBy: "retailer", SortDirection: "asc"}); err != nil {
+		t.Fatalf("List: %v", err)
+	} else if total != 1 {
+		t.Errorf("total stored = %d, want 1 (best-effort should store the valid receipt)", total)
+	}
+}
+
+const testReceiptID = "22222222-2222-4222-8222-222222222222"
+
+func TestUpdateReceiptStatus(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	rs := sampleReceiptStruct()
+	if err := receiptStore.Save(testReceiptID, &rs, 10); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	buf, err := json.Marshal(StatusUpdateCommand{Status: StatusResolved, Comment: "looks good"})
+	if err != nil {
+		t.Fatalf("marshaling request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPatch, "/receipts/"+testReceiptID+"/status", bytes.NewReader(buf))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+
+	sr, _, err := receiptStore.Get(testReceiptID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if sr.Status != StatusResolved {
+		t.Errorf("Status = %q, want %q", sr.Status, StatusResolved)
+	}
+}
+
+func TestUpdateReceiptStatusInvalidStatus(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	rs := sampleReceiptStruct()
+	if err := receiptStore.Save(testReceiptID, &rs, 10); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	buf, err := json.Marshal(StatusUpdateCommand{Status: "NOT_A_REAL_STATUS"})
+	if err != nil {
+		t.Fatalf("marshaling request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPatch, "/receipts/"+testReceiptID+"/status", bytes.NewReader(buf))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestUpdateReceiptStatusMissingReceipt(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	buf, err := json.Marshal(StatusUpdateCommand{Status: StatusResolved})
+	if err != nil {
+		t.Fatalf("marshaling request body: %v", err)
+	}
+	missingID := "11111111-1111-4111-8111-111111111111"
+	req := httptest.NewRequest(http.MethodPatch, "/receipts/"+missingID+"/status", bytes.NewReader(buf))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestUpdateReceiptStatusBulk(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	rs := sampleReceiptStruct()
+	if err := receiptStore.Save("id-1", &rs, 10); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := receiptStore.Save("id-2", &rs, 20); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	rec := postJSON(t, mux, "/receipts/status/bulk", BulkStatusUpdateCommand{
+		Status:     StatusNeedsAttention,
+		Comment:    "flagged for review",
+		ReceiptIds: []string{"id-1", "id-2", "does-not-exist"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp BulkStatusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("len(Results) = %d, want 3", len(resp.Results))
+	}
+	if resp.Results[0].Error != "" || resp.Results[1].Error != "" {
+		t.Errorf("Results = %+v, want the first two updates to succeed", resp.Results)
+	}
+	if resp.Results[2].Error == "" {
+		t.Errorf("Results[2].Error = %q, want an error for the missing receipt", resp.Results[2].Error)
+	}
+
+	sr, _, err := receiptStore.Get("id-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if sr.Status != StatusNeedsAttention {
+		t.Errorf("Status = %q, want %q", sr.Status, StatusNeedsAttention)
+	}
+}
+
+func TestUpdateReceiptStatusBulkNoReceiptIds(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	rec := postJSON(t, mux, "/receipts/status/bulk", BulkStatusUpdateCommand{Status: StatusResolved})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSearchReceiptsWrongMethod(t *testing.T) {
+	resetReceiptStore()
+	mux := newTestMux()
+
+	req := httptest.NewRequest(http.MethodGet, "/receipts/search", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,28 @@
+package idutil
+
+import "testing"
+
+func TestValidUUID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{"valid lowercase", "a1b2c3d4-e5f6-4789-abcd-0123456789ab", true},
+		{"valid uppercase", "A1B2C3D4-E5F6-4789-ABCD-0123456789AB", true},
+		{"too short", "a1b2c3d4-e5f6-4789-abcd-0123456789a", false},
+		{"too long", "a1b2c3d4-e5f6-4789-abcd-0123456789abc", false},
+		{"missing hyphen", "a1b2c3d4e5f6-4789-abcd-0123456789ab", false},
+		{"hyphen in wrong place", "a1b2c3d-4e5f6-4789-abcd-0123456789ab", false},
+		{"non-hex character", "g1b2c3d4-e5f6-4789-abcd-0123456789ab", false},
+		{"empty string", "", false},
+		{"plain word", "not-a-uuid-at-all-but-36-characters!", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidUUID(tt.id); got != tt.want {
+				t.Errorf("ValidUUID(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
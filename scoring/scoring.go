@@ -0,0 +1,101 @@
+// Package scoring computes the points awarded to a receipt, rule by rule, so that both the total
+// and an itemized breakdown can be derived from the same logic.
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/donghaof/receipt-processor-challenge/store"
+)
+
+// RuleResult is the outcome of a single scoring rule
+type RuleResult struct {
+	Rule   string
+	Points int64
+	Detail string `json:",omitempty"`
+}
+
+// ScoreResult is the total points awarded to a receipt, with the rules that contributed to it
+type ScoreResult struct {
+	Points    int64
+	Breakdown []RuleResult
+}
+
+// ScoreReceipt computes the points awarded to receipt and the itemized breakdown behind them
+func ScoreReceipt(receipt *store.Receipt) ScoreResult {
+	var result ScoreResult
+
+	// One point for every alphanumeric character in the retailer name
+	var alphanumeric int64
+	for _, char := range *receipt.Retailer {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			alphanumeric++
+		}
+	}
+	result.add(RuleResult{
+		Rule:   "retailer_alphanumeric",
+		Points: alphanumeric,
+		Detail: fmt.Sprintf("%d alphanumeric characters in '%s'", alphanumeric, *receipt.Retailer),
+	})
+
+	// 50 points if the total is a round dollar amount with no cents
+	total, _ := strconv.ParseFloat(*receipt.Total, 64)
+	if math.Mod(total, 1) == 0 {
+		result.add(RuleResult{Rule: "round_dollar_total", Points: 50})
+	}
+
+	// 25 points if the total is a multiple of `0.25`
+	if math.Mod(total, 0.25) == 0 {
+		result.add(RuleResult{Rule: "quarter_multiple_total", Points: 25})
+	}
+
+	// 5 points for every two items on the receipt
+	if itemPairPoints := int64(len(*receipt.Items)/2) * 5; itemPairPoints > 0 {
+		result.add(RuleResult{
+			Rule:   "item_pairs",
+			Points: itemPairPoints,
+			Detail: fmt.Sprintf("%d items on the receipt", len(*receipt.Items)),
+		})
+	}
+
+	// If the trimmed length of the item description is a multiple of 3, multiply the price by
+	// `0.2` and round up to the nearest integer. The result is the number of points earned
+	for _, item := range *receipt.Items {
+		trimmedLength := len(strings.TrimSpace(*item.ShortDescription))
+		if trimmedLength%3 != 0 {
+			continue
+		}
+		price, _ := strconv.ParseFloat(*item.Price, 64)
+		points := int64(math.Ceil(price * 0.2))
+		result.add(RuleResult{
+			Rule:   "item_desc_multiple_of_3",
+			Points: points,
+			Detail: fmt.Sprintf("'%s' (len %d) -> ceil(%.2f*0.2) = %d", *item.ShortDescription, trimmedLength, price, points),
+		})
+	}
+
+	// 6 points if the day in the purchase date is odd
+	parsedDate, _ := time.Parse("2006-01-02", *receipt.PurchaseDate)
+	if parsedDate.Day()%2 != 0 {
+		result.add(RuleResult{Rule: "odd_purchase_day", Points: 6})
+	}
+
+	// 10 points if the time of purchase is from 2:00pm up to, but not including, 4:00pm
+	parsedTime, _ := time.Parse("15:04", *receipt.PurchaseTime)
+	if parsedTime.Hour() >= 14 && parsedTime.Hour() < 16 {
+		result.add(RuleResult{Rule: "afternoon_purchase", Points: 10})
+	}
+
+	return result
+}
+
+// Appends rule to the breakdown and adds its points to the running total
+func (result *ScoreResult) add(rule RuleResult) {
+	result.Points += rule.Points
+	result.Breakdown = append(result.Breakdown, rule)
+}
@@ -0,0 +1,192 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/donghaof/receipt-processor-challenge/store"
+)
+
+func strPtr(s string) *string { return &s }
+
+func receiptWith(retailer, purchaseDate, purchaseTime, total string, items []store.Item) *store.Receipt {
+	return &store.Receipt{
+		Retailer:     &retailer,
+		PurchaseDate: &purchaseDate,
+		PurchaseTime: &purchaseTime,
+		Total:        &total,
+		Items:        &items,
+	}
+}
+
+func hasRule(result ScoreResult, rule string) (RuleResult, bool) {
+	for _, r := range result.Breakdown {
+		if r.Rule == rule {
+			return r, true
+		}
+	}
+	return RuleResult{}, false
+}
+
+func TestRetailerAlphanumeric(t *testing.T) {
+	receipt := receiptWith("Target & Co.", "2022-01-02", "10:00", "1.00", []store.Item{
+		{ShortDescription: strPtr("Pepsi"), Price: strPtr("1.00")},
+	})
+	result := ScoreReceipt(receipt)
+	rule, ok := hasRule(result, "retailer_alphanumeric")
+	if !ok {
+		t.Fatalf("expected retailer_alphanumeric rule in breakdown")
+	}
+	if rule.Points != 8 {
+		t.Errorf("Points = %d, want 8", rule.Points)
+	}
+}
+
+func TestRoundDollarTotal(t *testing.T) {
+	tests := []struct {
+		name  string
+		total string
+		want  bool
+	}{
+		{"round dollar", "35.00", true},
+		{"not round", "35.49", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := receiptWith("Target", "2022-01-02", "10:00", tt.total, []store.Item{
+				{ShortDescription: strPtr("Pepsi"), Price: strPtr("1.00")},
+			})
+			_, ok := hasRule(ScoreReceipt(receipt), "round_dollar_total")
+			if ok != tt.want {
+				t.Errorf("round_dollar_total present = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuarterMultipleTotal(t *testing.T) {
+	tests := []struct {
+		name  string
+		total string
+		want  bool
+	}{
+		{"multiple of quarter", "35.50", true},
+		{"not a multiple", "35.49", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := receiptWith("Target", "2022-01-02", "10:00", tt.total, []store.Item{
+				{ShortDescription: strPtr("Pepsi"), Price: strPtr("1.00")},
+			})
+			_, ok := hasRule(ScoreReceipt(receipt), "quarter_multiple_total")
+			if ok != tt.want {
+				t.Errorf("quarter_multiple_total present = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemPairs(t *testing.T) {
+	item := store.Item{ShortDescription: strPtr("Pepsi"), Price: strPtr("1.00")}
+	tests := []struct {
+		name       string
+		itemCount  int
+		wantPoints int64
+		wantRule   bool
+	}{
+		{"one item", 1, 0, false},
+		{"two items", 2, 5, true},
+		{"three items", 3, 5, true},
+		{"four items", 4, 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]store.Item, tt.itemCount)
+			for i := range items {
+				items[i] = item
+			}
+			receipt := receiptWith("Target", "2022-01-02", "10:00", "35.49", items)
+			rule, ok := hasRule(ScoreReceipt(receipt), "item_pairs")
+			if ok != tt.wantRule {
+				t.Fatalf("item_pairs present = %v, want %v", ok, tt.wantRule)
+			}
+			if ok && rule.Points != tt.wantPoints {
+				t.Errorf("Points = %d, want %d", rule.Points, tt.wantPoints)
+			}
+		})
+	}
+}
+
+func TestItemDescMultipleOf3(t *testing.T) {
+	receipt := receiptWith("Target", "2022-01-02", "10:00", "35.49", []store.Item{
+		{ShortDescription: strPtr("Emils Cheese Pizza"), Price: strPtr("12.25")},
+		{ShortDescription: strPtr("Pepsi"), Price: strPtr("1.00")},
+	})
+	result := ScoreReceipt(receipt)
+	rule, ok := hasRule(result, "item_desc_multiple_of_3")
+	if !ok {
+		t.Fatalf("expected item_desc_multiple_of_3 rule in breakdown")
+	}
+	if rule.Points != 3 {
+		t.Errorf("Points = %d, want 3", rule.Points)
+	}
+}
+
+func TestOddPurchaseDay(t *testing.T) {
+	tests := []struct {
+		name string
+		date string
+		want bool
+	}{
+		{"odd day", "2022-01-01", true},
+		{"even day", "2022-01-02", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := receiptWith("Target", tt.date, "10:00", "35.49", []store.Item{
+				{ShortDescription: strPtr("Pepsi"), Price: strPtr("1.00")},
+			})
+			_, ok := hasRule(ScoreReceipt(receipt), "odd_purchase_day")
+			if ok != tt.want {
+				t.Errorf("odd_purchase_day present = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestAfternoonPurchase(t *testing.T) {
+	tests := []struct {
+		name string
+		time string
+		want bool
+	}{
+		{"in window", "14:30", true},
+		{"before window", "13:59", false},
+		{"at window end", "16:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := receiptWith("Target", "2022-01-02", tt.time, "35.49", []store.Item{
+				{ShortDescription: strPtr("Pepsi"), Price: strPtr("1.00")},
+			})
+			_, ok := hasRule(ScoreReceipt(receipt), "afternoon_purchase")
+			if ok != tt.want {
+				t.Errorf("afternoon_purchase present = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestTotalMatchesBreakdownSum(t *testing.T) {
+	receipt := receiptWith("Target", "2022-01-01", "14:30", "35.00", []store.Item{
+		{ShortDescription: strPtr("Emils Cheese Pizza"), Price: strPtr("12.25")},
+		{ShortDescription: strPtr("Mountain Dew 12PK"), Price: strPtr("6.49")},
+	})
+	result := ScoreReceipt(receipt)
+	var sum int64
+	for _, rule := range result.Breakdown {
+		sum += rule.Points
+	}
+	if sum != result.Points {
+		t.Errorf("sum of breakdown points = %d, want %d", sum, result.Points)
+	}
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory ReceiptStore guarded by a RWMutex. Its contents do not survive a
+// restart.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	receipts map[string]*StoredReceipt
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{receipts: make(map[string]*StoredReceipt)}
+}
+
+func (m *MemoryStore) Save(id string, receipt *Receipt, points int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.receipts[id] = &StoredReceipt{
+		Id:      id,
+		Receipt: *receipt,
+		Points:  points,
+		Status:  "OPEN",
+		StatusHistory: []StatusEntry{
+			{Status: "OPEN", Timestamp: time.Now().UTC().Format(time.RFC3339)},
+		},
+	}
+	return nil
+}
+
+func (m *MemoryStore) Get(id string) (*StoredReceipt, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sr, ok := m.receipts[id]
+	if !ok {
+		return nil, false, nil
+	}
+	cp := *sr
+	return &cp, true, nil
+}
+
+func (m *MemoryStore) List(filter Filter, page Page) ([]StoredReceipt, int, error) {
+	m.mu.RLock()
+	matched := make([]StoredReceipt, 0, len(m.receipts))
+	for _, sr := range m.receipts {
+		if matchesFilter(sr, filter) {
+			matched = append(matched, *sr)
+		}
+	}
+	m.mu.RUnlock()
+
+	descending := strings.EqualFold(page.SortDirection, "desc")
+	sort.SliceStable(matched, func(i, j int) bool {
+		if descending {
+			return lessStoredReceipt(matched[j], matched[i], page.OrderBy)
+		}
+		return lessStoredReceipt(matched[i], matched[j], page.OrderBy)
+	})
+
+	totalCount := len(matched)
+	start := (page.Number - 1) * page.Size
+	if start < 0 || start > totalCount {
+		start = totalCount
+	}
+	end := start + page.Size
+	if end > totalCount {
+		end = totalCount
+	}
+	return matched[start:end], totalCount, nil
+}
+
+func (m *MemoryStore) UpdateStatus(id string, status string, comment string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sr, ok := m.receipts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	sr.Status = status
+	sr.StatusHistory = append(sr.StatusHistory, StatusEntry{
+		Status:    status,
+		Comment:   comment,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+	return nil
+}
+
+// Checks whether a stored receipt matches all of the populated fields of the filter
+func matchesFilter(sr *StoredReceipt, filter Filter) bool {
+	if filter.Retailer != nil && !strings.Contains(strings.ToLower(*sr.Retailer), strings.ToLower(*filter.Retailer)) {
+		return false
+	}
+	if filter.PurchaseDateFrom != nil && *sr.PurchaseDate < *filter.PurchaseDateFrom {
+		return false
+	}
+	if filter.PurchaseDateTo != nil && *sr.PurchaseDate > *filter.PurchaseDateTo {
+		return false
+	}
+	if filter.PurchaseTimeFrom != nil && *sr.PurchaseTime < *filter.PurchaseTimeFrom {
+		return false
+	}
+	if filter.PurchaseTimeTo != nil && *sr.PurchaseTime > *filter.PurchaseTimeTo {
+		return false
+	}
+	total, _ := strconv.ParseFloat(*sr.Total, 64)
+	if filter.MinTotal != nil && total < *filter.MinTotal {
+		return false
+	}
+	if filter.MaxTotal != nil && total > *filter.MaxTotal {
+		return false
+	}
+	itemCount := len(*sr.Items)
+	if filter.MinItems != nil && itemCount < *filter.MinItems {
+		return false
+	}
+	if filter.MaxItems != nil && itemCount > *filter.MaxItems {
+		return false
+	}
+	if filter.MinPoints != nil && sr.Points < *filter.MinPoints {
+		return false
+	}
+	if filter.MaxPoints != nil && sr.Points > *filter.MaxPoints {
+		return false
+	}
+	if filter.Status != nil && !strings.EqualFold(sr.Status, *filter.Status) {
+		return false
+	}
+	return true
+}
+
+// Compares two stored receipts by the requested field, ascending
+func lessStoredReceipt(a, b StoredReceipt, orderBy string) bool {
+	switch strings.ToLower(orderBy) {
+	case "purchasedate":
+		return *a.PurchaseDate < *b.PurchaseDate
+	case "purchasetime":
+		return *a.PurchaseTime < *b.PurchaseTime
+	case "total":
+		aTotal, _ := strconv.ParseFloat(*a.Total, 64)
+		bTotal, _ := strconv.ParseFloat(*b.Total, 64)
+		return aTotal < bTotal
+	case "points":
+		return a.Points < b.Points
+	default:
+		return strings.ToLower(*a.Retailer) < strings.ToLower(*b.Retailer)
+	}
+}
@@ -0,0 +1,321 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore is a ReceiptStore backed by a SQLite database, opened with the CGO-free
+// modernc.org/sqlite driver.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (and migrates, if necessary) the SQLite database at dsn
+func NewSQLiteStore(dsn string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite store: %w", err)
+	}
+	// SQLite only supports one writer at a time; capping the pool at a single connection also
+	// sidesteps each pooled connection to ":memory:" otherwise seeing its own empty database.
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStore{db: db}
+	if err := s.migrate(); err != nil {
+		return nil, fmt.Errorf("migrating sqlite store: %w", err)
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) migrate() error {
+	_, err := s.db.Exec(`
+CREATE TABLE IF NOT EXISTS receipts (
+	id TEXT PRIMARY KEY,
+	retailer TEXT NOT NULL,
+	purchase_date TEXT NOT NULL,
+	purchase_time TEXT NOT NULL,
+	total TEXT NOT NULL,
+	points INTEGER NOT NULL,
+	status TEXT NOT NULL DEFAULT 'OPEN',
+	created_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS receipt_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	receipt_id TEXT NOT NULL REFERENCES receipts(id),
+	position INTEGER NOT NULL,
+	short_description TEXT NOT NULL,
+	price TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS receipt_status_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	receipt_id TEXT NOT NULL REFERENCES receipts(id),
+	status TEXT NOT NULL,
+	comment TEXT NOT NULL,
+	created_at TEXT NOT NULL
+);
+`)
+	return err
+}
+
+func (s *SQLiteStore) Save(id string, receipt *Receipt, points int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err = tx.Exec(
+		`INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total, points, status, created_at) VALUES (?, ?, ?, ?, ?, ?, 'OPEN', ?)`,
+		id, *receipt.Retailer, *receipt.PurchaseDate, *receipt.PurchaseTime, *receipt.Total, points, now,
+	)
+	if err != nil {
+		return err
+	}
+	for i, item := range *receipt.Items {
+		if _, err = tx.Exec(
+			`INSERT INTO receipt_items (receipt_id, position, short_description, price) VALUES (?, ?, ?, ?)`,
+			id, i, *item.ShortDescription, *item.Price,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err = tx.Exec(
+		`INSERT INTO receipt_status_history (receipt_id, status, comment, created_at) VALUES (?, 'OPEN', '', ?)`,
+		id, now,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) Get(id string) (*StoredReceipt, bool, error) {
+	sr := &StoredReceipt{Id: id}
+	row := s.db.QueryRow(`SELECT retailer, purchase_date, purchase_time, total, points, status FROM receipts WHERE id = ?`, id)
+	var retailer, purchaseDate, purchaseTime, total, status string
+	if err := row.Scan(&retailer, &purchaseDate, &purchaseTime, &total, &sr.Points, &status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	sr.Retailer = &retailer
+	sr.PurchaseDate = &purchaseDate
+	sr.PurchaseTime = &purchaseTime
+	sr.Total = &total
+	sr.Status = status
+
+	items, err := s.itemsFor(id)
+	if err != nil {
+		return nil, false, err
+	}
+	sr.Items = &items
+
+	history, err := s.historyFor(id)
+	if err != nil {
+		return nil, false, err
+	}
+	sr.StatusHistory = history
+
+	return sr, true, nil
+}
+
+func (s *SQLiteStore) itemsFor(id string) ([]Item, error) {
+	rows, err := s.db.Query(`SELECT short_description, price FROM receipt_items WHERE receipt_id = ? ORDER BY position`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]Item, 0)
+	for rows.Next() {
+		var shortDescription, price string
+		if err := rows.Scan(&shortDescription, &price); err != nil {
+			return nil, err
+		}
+		items = append(items, Item{ShortDescription: &shortDescription, Price: &price})
+	}
+	return items, rows.Err()
+}
+
+func (s *SQLiteStore) historyFor(id string) ([]StatusEntry, error) {
+	rows, err := s.db.Query(`SELECT status, comment, created_at FROM receipt_status_history WHERE receipt_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]StatusEntry, 0)
+	for rows.Next() {
+		var entry StatusEntry
+		if err := rows.Scan(&entry.Status, &entry.Comment, &entry.Timestamp); err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
+func (s *SQLiteStore) List(filter Filter, page Page) ([]StoredReceipt, int, error) {
+	where, args := whereClauseFor(filter)
+
+	var totalCount int
+	countQuery := "SELECT COUNT(*) FROM receipts" + where
+	if err := s.db.QueryRow(countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT id, retailer, purchase_date, purchase_time, total, points, status FROM receipts" + where
+	query += " ORDER BY " + orderByColumn(page.OrderBy)
+	if strings.EqualFold(page.SortDirection, "desc") {
+		query += " DESC"
+	} else {
+		query += " ASC"
+	}
+	query += " LIMIT ? OFFSET ?"
+	args = append(args, page.Size, (page.Number-1)*page.Size)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Scanning the receipt rows first and closing rows before issuing the per-receipt item
+	// queries below, since the pool is capped at a single connection
+	results := make([]StoredReceipt, 0)
+	for rows.Next() {
+		var sr StoredReceipt
+		var retailer, purchaseDate, purchaseTime, total, status string
+		if err := rows.Scan(&sr.Id, &retailer, &purchaseDate, &purchaseTime, &total, &sr.Points, &status); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		sr.Retailer = &retailer
+		sr.PurchaseDate = &purchaseDate
+		sr.PurchaseTime = &purchaseTime
+		sr.Total = &total
+		sr.Status = status
+		results = append(results, sr)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return nil, 0, rowsErr
+	}
+
+	for i := range results {
+		items, err := s.itemsFor(results[i].Id)
+		if err != nil {
+			return nil, 0, err
+		}
+		results[i].Items = &items
+	}
+	return results, totalCount, nil
+}
+
+func (s *SQLiteStore) UpdateStatus(id string, status string, comment string) error {
+	res, err := s.db.Exec(`UPDATE receipts SET status = ? WHERE id = ?`, status, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO receipt_status_history (receipt_id, status, comment, created_at) VALUES (?, ?, ?, ?)`,
+		id, status, comment, time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// Builds a SQL WHERE clause and its positional arguments from filter
+func whereClauseFor(filter Filter) (string, []any) {
+	var clauses []string
+	var args []any
+
+	if filter.Retailer != nil {
+		clauses = append(clauses, "retailer LIKE ? ESCAPE '\\' COLLATE NOCASE")
+		args = append(args, "%"+escapeLikePattern(*filter.Retailer)+"%")
+	}
+	if filter.PurchaseDateFrom != nil {
+		clauses = append(clauses, "purchase_date >= ?")
+		args = append(args, *filter.PurchaseDateFrom)
+	}
+	if filter.PurchaseDateTo != nil {
+		clauses = append(clauses, "purchase_date <= ?")
+		args = append(args, *filter.PurchaseDateTo)
+	}
+	if filter.PurchaseTimeFrom != nil {
+		clauses = append(clauses, "purchase_time >= ?")
+		args = append(args, *filter.PurchaseTimeFrom)
+	}
+	if filter.PurchaseTimeTo != nil {
+		clauses = append(clauses, "purchase_time <= ?")
+		args = append(args, *filter.PurchaseTimeTo)
+	}
+	if filter.MinTotal != nil {
+		clauses = append(clauses, "CAST(total AS REAL) >= ?")
+		args = append(args, *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		clauses = append(clauses, "CAST(total AS REAL) <= ?")
+		args = append(args, *filter.MaxTotal)
+	}
+	if filter.MinPoints != nil {
+		clauses = append(clauses, "points >= ?")
+		args = append(args, *filter.MinPoints)
+	}
+	if filter.MaxPoints != nil {
+		clauses = append(clauses, "points <= ?")
+		args = append(args, *filter.MaxPoints)
+	}
+	if filter.Status != nil {
+		clauses = append(clauses, "status = ? COLLATE NOCASE")
+		args = append(args, *filter.Status)
+	}
+	if filter.MinItems != nil {
+		clauses = append(clauses, "(SELECT COUNT(*) FROM receipt_items WHERE receipt_id = receipts.id) >= ?")
+		args = append(args, *filter.MinItems)
+	}
+	if filter.MaxItems != nil {
+		clauses = append(clauses, "(SELECT COUNT(*) FROM receipt_items WHERE receipt_id = receipts.id) <= ?")
+		args = append(args, *filter.MaxItems)
+	}
+
+	if len(clauses) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// Escapes the LIKE wildcards %, _ and the escape character itself so a user-supplied substring is
+// matched literally rather than as a pattern
+func escapeLikePattern(s string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+	return replacer.Replace(s)
+}
+
+// Maps an OrderBy field name to the column it corresponds to, defaulting to retailer
+func orderByColumn(orderBy string) string {
+	switch strings.ToLower(orderBy) {
+	case "purchasedate":
+		return "purchase_date"
+	case "purchasetime":
+		return "purchase_time"
+	case "total":
+		return "CAST(total AS REAL)"
+	case "points":
+		return "points"
+	default:
+		return "retailer COLLATE NOCASE"
+	}
+}
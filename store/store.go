@@ -0,0 +1,80 @@
+// Package store provides the persistence layer for processed receipts, along with the receipt
+// data types it persists. Scoring lives in the scoring package; this package only knows how to
+// save, fetch, list and update the receipts it is handed.
+package store
+
+import "errors"
+
+// ErrNotFound is returned by UpdateStatus when the given receipt id does not exist
+var ErrNotFound = errors.New("receipt not found")
+
+// Item is a single line item on a receipt
+type Item struct {
+	ShortDescription *string
+	Price            *string
+}
+
+// Receipt is the data submitted for a single receipt
+type Receipt struct {
+	Retailer     *string
+	PurchaseDate *string
+	PurchaseTime *string
+	Items        *[]Item
+	Total        *string
+}
+
+// StatusEntry is one entry in a receipt's status history
+type StatusEntry struct {
+	Status    string
+	Comment   string
+	Timestamp string
+}
+
+// StoredReceipt is a receipt as it comes back out of a ReceiptStore, with its id, computed
+// points, and current status alongside the original submitted fields
+type StoredReceipt struct {
+	Id string
+	Receipt
+	Points        int64
+	Status        string
+	StatusHistory []StatusEntry
+}
+
+// Filter narrows down a List call; a nil field means "don't filter on this"
+type Filter struct {
+	Retailer         *string
+	PurchaseDateFrom *string
+	PurchaseDateTo   *string
+	PurchaseTimeFrom *string
+	PurchaseTimeTo   *string
+	MinTotal         *float64
+	MaxTotal         *float64
+	MinItems         *int
+	MaxItems         *int
+	MinPoints        *int64
+	MaxPoints        *int64
+	Status           *string
+}
+
+// Page describes the paging and ordering of a List call
+type Page struct {
+	Number        int
+	Size          int
+	OrderBy       string
+	SortDirection string
+}
+
+// ReceiptStore is the persistence interface implemented by the memory and SQL-backed stores.
+// Implementations must be safe for concurrent use.
+type ReceiptStore interface {
+	// Save persists a receipt and its precomputed points under id, defaulting its status to OPEN
+	Save(id string, receipt *Receipt, points int64) error
+	// Get returns the receipt stored under id, or ok == false if no such receipt exists
+	Get(id string) (receipt *StoredReceipt, ok bool, err error)
+	// List returns the receipts matching filter, ordered and paged per page, along with the
+	// total number of matches before paging was applied
+	List(filter Filter, page Page) (results []StoredReceipt, totalCount int, err error)
+	// UpdateStatus appends a status history entry and updates the receipt's current status,
+	// returning ErrNotFound if id does not exist
+	UpdateStatus(id string, status string, comment string) error
+}
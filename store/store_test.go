@@ -0,0 +1,180 @@
+package store
+
+import (
+	"testing"
+)
+
+func newStores(t *testing.T) map[string]ReceiptStore {
+	sqliteStore, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("opening sqlite store: %v", err)
+	}
+	return map[string]ReceiptStore{
+		"memory": NewMemoryStore(),
+		"sqlite": sqliteStore,
+	}
+}
+
+func sampleReceipt() *Receipt {
+	retailer := "Target"
+	purchaseDate := "2022-01-01"
+	purchaseTime := "13:01"
+	total := "35.35"
+	items := []Item{
+		{ShortDescription: strPtr("Mountain Dew 12PK"), Price: strPtr("6.49")},
+		{ShortDescription: strPtr("Emils Cheese Pizza"), Price: strPtr("12.25")},
+	}
+	return &Receipt{
+		Retailer:     &retailer,
+		PurchaseDate: &purchaseDate,
+		PurchaseTime: &purchaseTime,
+		Items:        &items,
+		Total:        &total,
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestSaveAndGet(t *testing.T) {
+	for name, s := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Save("id-1", sampleReceipt(), 42); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			sr, ok, err := s.Get("id-1")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if !ok {
+				t.Fatalf("Get: expected receipt to be found")
+			}
+			if sr.Points != 42 {
+				t.Errorf("Points = %d, want 42", sr.Points)
+			}
+			if sr.Status != "OPEN" {
+				t.Errorf("Status = %q, want OPEN", sr.Status)
+			}
+			if len(*sr.Items) != 2 {
+				t.Errorf("len(Items) = %d, want 2", len(*sr.Items))
+			}
+			if len(sr.StatusHistory) != 1 {
+				t.Fatalf("len(StatusHistory) = %d, want 1", len(sr.StatusHistory))
+			}
+			if sr.StatusHistory[0].Status != "OPEN" {
+				t.Errorf("StatusHistory[0].Status = %q, want OPEN", sr.StatusHistory[0].Status)
+			}
+			if sr.StatusHistory[0].Timestamp == "" {
+				t.Errorf("StatusHistory[0].Timestamp = %q, want a non-empty timestamp", sr.StatusHistory[0].Timestamp)
+			}
+		})
+	}
+}
+
+func TestGetMissing(t *testing.T) {
+	for name, s := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, ok, err := s.Get("does-not-exist")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if ok {
+				t.Fatalf("Get: expected receipt not to be found")
+			}
+		})
+	}
+}
+
+func TestListFilterAndPage(t *testing.T) {
+	for name, s := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Save("id-1", sampleReceipt(), 10); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if err := s.Save("id-2", sampleReceipt(), 90); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			minPoints := int64(50)
+			results, total, err := s.List(Filter{MinPoints: &minPoints}, Page{Number: 1, Size: 10, OrderBy: "points", SortDirection: "asc"})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if total != 1 {
+				t.Fatalf("total = %d, want 1", total)
+			}
+			if len(results) != 1 || results[0].Id != "id-2" {
+				t.Fatalf("results = %+v, want only id-2", results)
+			}
+
+			all, total, err := s.List(Filter{}, Page{Number: 1, Size: 1, OrderBy: "points", SortDirection: "asc"})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if total != 2 {
+				t.Fatalf("total = %d, want 2", total)
+			}
+			if len(all) != 1 || all[0].Id != "id-1" {
+				t.Fatalf("results = %+v, want first page to be id-1", all)
+			}
+		})
+	}
+}
+
+func TestListFilterRetailerLiteralWildcards(t *testing.T) {
+	for name, s := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Save("id-1", sampleReceipt(), 10); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if err := s.Save("id-2", sampleReceipt(), 20); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			retailer := "_"
+			_, total, err := s.List(Filter{Retailer: &retailer}, Page{Number: 1, Size: 10, OrderBy: "retailer", SortDirection: "asc"})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if total != 0 {
+				t.Errorf("total = %d, want 0 ('_' should match literally, not as a LIKE wildcard)", total)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	for name, s := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Save("id-1", sampleReceipt(), 10); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if err := s.UpdateStatus("id-1", "RESOLVED", "looks good"); err != nil {
+				t.Fatalf("UpdateStatus: %v", err)
+			}
+			sr, _, err := s.Get("id-1")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if sr.Status != "RESOLVED" {
+				t.Errorf("Status = %q, want RESOLVED", sr.Status)
+			}
+			if len(sr.StatusHistory) != 2 {
+				t.Fatalf("len(StatusHistory) = %d, want 2", len(sr.StatusHistory))
+			}
+			last := sr.StatusHistory[1]
+			if last.Status != "RESOLVED" {
+				t.Errorf("StatusHistory[1].Status = %q, want RESOLVED", last.Status)
+			}
+			if last.Comment != "looks good" {
+				t.Errorf("StatusHistory[1].Comment = %q, want %q", last.Comment, "looks good")
+			}
+			if last.Timestamp == "" {
+				t.Errorf("StatusHistory[1].Timestamp = %q, want a non-empty timestamp", last.Timestamp)
+			}
+
+			if err := s.UpdateStatus("does-not-exist", "RESOLVED", ""); err != ErrNotFound {
+				t.Errorf("UpdateStatus on missing id = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}